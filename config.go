@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Config holds all the settings the demo needs to talk to a MinIO (or any
+// S3-compatible) endpoint. Values are resolved in the following order, each
+// overriding the last: built-in defaults -> .env file -> environment
+// variables -> command-line flags.
+type Config struct {
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	BucketName      string
+	Location        string
+	UseSSL          bool
+	UploadDir       string
+	DownloadDir     string
+
+	// ParseableURL is the ingest endpoint the app's own JSON logs are
+	// shipped to, in addition to stdout. Left empty, shipping is disabled.
+	ParseableURL      string
+	ParseableUsername string
+	ParseablePassword string
+	ParseableStream   string
+
+	// Workload settings drive the continuous load generator: how many
+	// workers run concurrently, how fast they're allowed to issue
+	// requests, and when to stop.
+	WorkloadWorkers         int
+	WorkloadRPS             float64
+	WorkloadDuration        time.Duration
+	WorkloadMaxOps          int64
+	WorkloadSummaryInterval time.Duration
+
+	// WorkloadOpMix is a "PUT=40,GET=30,LIST=15,STAT=10,DELETE=5"-style
+	// override for the operation mix. Empty uses the built-in default mix.
+	WorkloadOpMix string
+
+	// Bucket notification settings wire up a second, event-driven
+	// ingestion path alongside MinIO's own audit-log forwarding.
+	NotificationARN       string
+	ParseableEventsURL    string
+	ParseableEventsStream string
+}
+
+// defaultConfig mirrors the constants this demo used to hardcode, except for
+// the access and secret keys, which default to empty so that Credentials
+// falls through to the rest of the credential chain.
+func defaultConfig() *Config {
+	return &Config{
+		Endpoint:        "localhost:9000",
+		AccessKeyID:     "",
+		SecretAccessKey: "",
+		BucketName:      "go-test-bucket",
+		Location:        "us-east-1",
+		UseSSL:          false,
+		UploadDir:       "./uploads",
+		DownloadDir:     "./downloads",
+
+		ParseableStream: "minio_app_log",
+
+		WorkloadWorkers:         4,
+		WorkloadRPS:             10,
+		WorkloadDuration:        0,
+		WorkloadMaxOps:          0,
+		WorkloadSummaryInterval: 10 * time.Second,
+
+		ParseableEventsStream: "minio_bucket_events",
+	}
+}
+
+// loadDotEnv reads KEY=VALUE pairs from path (if it exists) and applies them
+// to the process environment without clobbering variables already set, so
+// real environment variables always win over a checked-in .env file.
+func loadDotEnv(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if _, exists := os.LookupEnv(key); !exists {
+			os.Setenv(key, value)
+		}
+	}
+}
+
+func envOrDefault(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envBoolOrDefault(key string, fallback bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envIntOrDefault(key string, fallback int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envFloatOrDefault(key string, fallback float64) float64 {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envDurationOrDefault(key string, fallback time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// LoadConfig builds the demo's Config from a .env file, MINIO_* environment
+// variables, and command-line flags, in that order of increasing priority.
+func LoadConfig() *Config {
+	loadDotEnv(".env")
+
+	cfg := defaultConfig()
+	cfg.Endpoint = envOrDefault("MINIO_ENDPOINT", cfg.Endpoint)
+	cfg.AccessKeyID = envOrDefault("MINIO_ACCESS_KEY", cfg.AccessKeyID)
+	cfg.SecretAccessKey = envOrDefault("MINIO_SECRET_KEY", cfg.SecretAccessKey)
+	cfg.BucketName = envOrDefault("MINIO_BUCKET", cfg.BucketName)
+	cfg.Location = envOrDefault("MINIO_REGION", cfg.Location)
+	cfg.UseSSL = envBoolOrDefault("MINIO_USE_SSL", cfg.UseSSL)
+	cfg.ParseableURL = envOrDefault("PARSEABLE_URL", cfg.ParseableURL)
+	cfg.ParseableUsername = envOrDefault("PARSEABLE_USERNAME", cfg.ParseableUsername)
+	cfg.ParseablePassword = envOrDefault("PARSEABLE_PASSWORD", cfg.ParseablePassword)
+	cfg.ParseableStream = envOrDefault("PARSEABLE_STREAM", cfg.ParseableStream)
+	cfg.WorkloadWorkers = envIntOrDefault("WORKLOAD_WORKERS", cfg.WorkloadWorkers)
+	cfg.WorkloadRPS = envFloatOrDefault("WORKLOAD_RPS", cfg.WorkloadRPS)
+	cfg.WorkloadDuration = envDurationOrDefault("WORKLOAD_DURATION", cfg.WorkloadDuration)
+	cfg.WorkloadMaxOps = int64(envIntOrDefault("WORKLOAD_MAX_OPS", int(cfg.WorkloadMaxOps)))
+	cfg.WorkloadOpMix = envOrDefault("WORKLOAD_OP_MIX", cfg.WorkloadOpMix)
+	cfg.NotificationARN = envOrDefault("MINIO_NOTIFICATION_ARN", cfg.NotificationARN)
+	cfg.ParseableEventsURL = envOrDefault("PARSEABLE_EVENTS_URL", cfg.ParseableEventsURL)
+	cfg.ParseableEventsStream = envOrDefault("PARSEABLE_EVENTS_STREAM", cfg.ParseableEventsStream)
+
+	flag.StringVar(&cfg.Endpoint, "endpoint", cfg.Endpoint, "MinIO/S3 endpoint host:port")
+	flag.StringVar(&cfg.AccessKeyID, "access-key", cfg.AccessKeyID, "static access key (leave unset to let AWS/MinIO env vars, a shared credentials file, or IAM take over)")
+	flag.StringVar(&cfg.SecretAccessKey, "secret-key", cfg.SecretAccessKey, "static secret key (leave unset to let AWS/MinIO env vars, a shared credentials file, or IAM take over)")
+	flag.StringVar(&cfg.BucketName, "bucket", cfg.BucketName, "bucket to exercise")
+	flag.StringVar(&cfg.Location, "region", cfg.Location, "bucket region")
+	flag.BoolVar(&cfg.UseSSL, "use-ssl", cfg.UseSSL, "use HTTPS when talking to the endpoint")
+	flag.StringVar(&cfg.UploadDir, "upload-dir", cfg.UploadDir, "local directory used to stage generated files before upload")
+	flag.StringVar(&cfg.DownloadDir, "download-dir", cfg.DownloadDir, "local directory used to store downloaded files")
+	flag.StringVar(&cfg.ParseableURL, "parseable-url", cfg.ParseableURL, "Parseable ingest URL the app's own logs are shipped to (empty disables shipping)")
+	flag.StringVar(&cfg.ParseableUsername, "parseable-username", cfg.ParseableUsername, "basic auth username for the Parseable ingest endpoint")
+	flag.StringVar(&cfg.ParseablePassword, "parseable-password", cfg.ParseablePassword, "basic auth password for the Parseable ingest endpoint")
+	flag.StringVar(&cfg.ParseableStream, "parseable-stream", cfg.ParseableStream, "Parseable stream name the app's own logs are tagged with")
+	flag.IntVar(&cfg.WorkloadWorkers, "workload-workers", cfg.WorkloadWorkers, "number of concurrent workload workers")
+	flag.Float64Var(&cfg.WorkloadRPS, "workload-rps", cfg.WorkloadRPS, "target requests per second across all workers")
+	flag.DurationVar(&cfg.WorkloadDuration, "workload-duration", cfg.WorkloadDuration, "how long to run the workload for (0 = until max-ops or interrupted)")
+	var workloadMaxOps int
+	flag.IntVar(&workloadMaxOps, "workload-max-ops", int(cfg.WorkloadMaxOps), "total operations to run before stopping (0 = unbounded)")
+	flag.StringVar(&cfg.WorkloadOpMix, "workload-op-mix", cfg.WorkloadOpMix, "operation mix override, e.g. \"PUT=40,GET=30,LIST=15,STAT=10,DELETE=5\" (empty uses the default mix)")
+	flag.StringVar(&cfg.NotificationARN, "notification-arn", cfg.NotificationARN, "SNS/SQS/webhook ARN to register via SetBucketNotification (empty skips registration)")
+	flag.StringVar(&cfg.ParseableEventsURL, "parseable-events-url", cfg.ParseableEventsURL, "Parseable ingest URL bucket notification events are forwarded to (empty disables forwarding)")
+	flag.StringVar(&cfg.ParseableEventsStream, "parseable-events-stream", cfg.ParseableEventsStream, "Parseable stream name bucket notification events are tagged with")
+	flag.Parse()
+	cfg.WorkloadMaxOps = int64(workloadMaxOps)
+
+	return cfg
+}
+
+// Credentials builds a credential chain in the order restic's S3 backend
+// uses: static credentials (if explicitly configured), AWS env vars, MinIO
+// env vars, the AWS shared credentials file, and finally the EC2/ECS IAM
+// instance profile. The first provider in the chain that finds usable
+// credentials wins, so the demo can be pointed at real S3-compatible
+// endpoints without recompiling.
+//
+// credentials.Chain only skips a provider when both of its fields come back
+// empty, so Static is only added to the chain when the caller actually
+// configured an access/secret key (via -access-key/-secret-key or
+// MINIO_ACCESS_KEY/MINIO_SECRET_KEY) -- otherwise it would always "win" with
+// empty credentials and the rest of the chain would never get a turn.
+func (c *Config) Credentials() *credentials.Credentials {
+	var providers []credentials.Provider
+
+	if c.AccessKeyID != "" && c.SecretAccessKey != "" {
+		providers = append(providers, &credentials.Static{
+			Value: credentials.Value{
+				AccessKeyID:     c.AccessKeyID,
+				SecretAccessKey: c.SecretAccessKey,
+			},
+		})
+	}
+
+	providers = append(providers,
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{},
+	)
+
+	return credentials.NewChainCredentials(providers)
+}
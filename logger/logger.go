@@ -0,0 +1,216 @@
+// Package logger is a minimal, MinIO-inspired structured logger for the
+// demo. It mirrors the shape of MinIO's own internal logger.LogIf(ctx, err)
+// helper: callers attach context (operation, bucket, object, request ID,
+// timing) to a context.Context, then call LogIf/Info/FatalIf to emit a
+// single newline-delimited JSON record to stdout and, if configured, to a
+// Parseable ingest endpoint over HTTP.
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log record.
+type Level string
+
+const (
+	LevelInfo  Level = "INFO"
+	LevelError Level = "ERROR"
+	LevelFatal Level = "FATAL"
+)
+
+// Record is the JSON shape shipped to stdout and to Parseable.
+type Record struct {
+	Time       time.Time `json:"time"`
+	Level      Level     `json:"level"`
+	Op         string    `json:"op,omitempty"`
+	Bucket     string    `json:"bucket,omitempty"`
+	Object     string    `json:"object,omitempty"`
+	Size       int64     `json:"size,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Trace      []string  `json:"trace,omitempty"`
+}
+
+type ctxKey int
+
+const (
+	opKey ctxKey = iota
+	bucketKey
+	objectKey
+	requestIDKey
+	sizeKey
+	durationKey
+)
+
+// WithOp attaches the operation name (e.g. "upload", "list") to ctx, the
+// same way MinIO's logger.WithOp(ctx, "...") tags a request with the verb
+// being audited.
+func WithOp(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, opKey, op)
+}
+
+// WithBucket attaches the bucket name to ctx.
+func WithBucket(ctx context.Context, bucket string) context.Context {
+	return context.WithValue(ctx, bucketKey, bucket)
+}
+
+// WithObject attaches the object key to ctx.
+func WithObject(ctx context.Context, object string) context.Context {
+	return context.WithValue(ctx, objectKey, object)
+}
+
+// WithRequestID attaches a request/correlation ID to ctx.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// WithSize attaches an object/payload size in bytes to ctx.
+func WithSize(ctx context.Context, size int64) context.Context {
+	return context.WithValue(ctx, sizeKey, size)
+}
+
+// WithDuration attaches how long the operation took to ctx.
+func WithDuration(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, durationKey, d)
+}
+
+func stringFrom(ctx context.Context, key ctxKey) string {
+	v, _ := ctx.Value(key).(string)
+	return v
+}
+
+// Target holds where log records should be shipped in addition to stdout.
+type Target struct {
+	URL      string
+	Username string
+	Password string
+	Stream   string
+}
+
+var (
+	mu         sync.RWMutex
+	target     Target
+	httpClient = &http.Client{Timeout: 5 * time.Second}
+)
+
+// Configure sets (or clears, with an empty URL) the Parseable ingest target
+// that every subsequent LogIf/Info/FatalIf call ships records to.
+func Configure(t Target) {
+	mu.Lock()
+	defer mu.Unlock()
+	target = t
+}
+
+// trace captures the call stack above the logger package, so records carry
+// enough context to pinpoint where an error was raised.
+func trace() []string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	lines := make([]string, 0, n)
+	for {
+		frame, more := frames.Next()
+		lines = append(lines, fmt.Sprintf("%s:%d", frame.Function, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return lines
+}
+
+func write(ctx context.Context, level Level, err error, message string) {
+	rec := Record{
+		Time:      time.Now().UTC(),
+		Level:     level,
+		Op:        stringFrom(ctx, opKey),
+		Bucket:    stringFrom(ctx, bucketKey),
+		Object:    stringFrom(ctx, objectKey),
+		RequestID: stringFrom(ctx, requestIDKey),
+		Message:   message,
+	}
+	if size, ok := ctx.Value(sizeKey).(int64); ok {
+		rec.Size = size
+	}
+	if d, ok := ctx.Value(durationKey).(time.Duration); ok {
+		rec.DurationMs = d.Milliseconds()
+	}
+	if err != nil {
+		rec.Error = err.Error()
+		rec.Trace = trace()
+	}
+
+	line, marshalErr := json.Marshal(rec)
+	if marshalErr != nil {
+		fmt.Fprintf(os.Stderr, "logger: failed to marshal record: %v\n", marshalErr)
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, string(line))
+	ship(line)
+}
+
+// ship forwards line to the configured Parseable ingest endpoint in the
+// background, best-effort; ingestion failures must never block the demo.
+func ship(line []byte) {
+	mu.RLock()
+	t := target
+	mu.RUnlock()
+
+	if t.URL == "" {
+		return
+	}
+
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, t.URL, bytes.NewReader(line))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if t.Stream != "" {
+			req.Header.Set("X-P-Stream", t.Stream)
+		}
+		if t.Username != "" {
+			req.SetBasicAuth(t.Username, t.Password)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+	}()
+}
+
+// LogIf logs err at ERROR level if it is non-nil, and is a no-op otherwise
+// -- mirroring MinIO's own logger.LogIf(ctx, err) idiom.
+func LogIf(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	write(ctx, LevelError, err, "")
+}
+
+// Info logs message at INFO level.
+func Info(ctx context.Context, message string) {
+	write(ctx, LevelInfo, nil, message)
+}
+
+// FatalIf logs err at FATAL level if it is non-nil and then exits the
+// process, mirroring the demo's previous log.Fatalf calls.
+func FatalIf(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	write(ctx, LevelFatal, err, "")
+	os.Exit(1)
+}
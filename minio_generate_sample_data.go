@@ -5,7 +5,6 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -14,50 +13,35 @@ import (
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
-)
-
-const (
-	endpoint        = "localhost:9000"
-	accessKeyID     = "minioadmin"
-	secretAccessKey = "minioadmin"
-	bucketName      = "go-test-bucket"
-	location        = "us-east-1"
-	useSSL          = false
-)
 
-var (
-	uploadDir   = "./uploads"
-	downloadDir = "./downloads"
+	"github.com/RO-29/minio-parseable-audit-logging/logger"
 )
 
-func ensureDirExists(dir string) {
+func ensureDirExists(ctx context.Context, dir string) {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		err := os.MkdirAll(dir, 0755)
-		if err != nil {
-			log.Fatalf("❌ Error creating directory %s: %v", dir, err)
-		}
+		logger.FatalIf(logger.WithOp(ctx, "mkdir"), err)
 	}
 }
 
-func createBucketIfNotExists(minioClient *minio.Client) error {
-	ctx := context.Background()
-	fmt.Printf("🔍 Checking if bucket '%s' exists...\n", bucketName)
+func createBucketIfNotExists(minioClient *minio.Client, cfg *Config) error {
+	ctx := logger.WithOp(context.Background(), "create-bucket")
+	logger.Info(ctx, fmt.Sprintf("checking if bucket '%s' exists", cfg.BucketName))
 
-	exists, err := minioClient.BucketExists(ctx, bucketName)
+	exists, err := minioClient.BucketExists(ctx, cfg.BucketName)
 	if err != nil {
-		return fmt.Errorf("❌ Error checking bucket existence: %v", err)
+		return fmt.Errorf("error checking bucket existence: %v", err)
 	}
 
 	if !exists {
-		fmt.Printf("📦 Creating bucket '%s'...\n", bucketName)
-		err = minioClient.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{Region: location})
+		logger.Info(ctx, fmt.Sprintf("creating bucket '%s'", cfg.BucketName))
+		err = minioClient.MakeBucket(ctx, cfg.BucketName, minio.MakeBucketOptions{Region: cfg.Location})
 		if err != nil {
-			return fmt.Errorf("❌ Error creating bucket: %v", err)
+			return fmt.Errorf("error creating bucket: %v", err)
 		}
-		fmt.Printf("✅ Bucket '%s' created successfully\n", bucketName)
+		logger.Info(ctx, fmt.Sprintf("bucket '%s' created successfully", cfg.BucketName))
 	} else {
-		fmt.Printf("✅ Bucket '%s' already exists\n", bucketName)
+		logger.Info(ctx, fmt.Sprintf("bucket '%s' already exists", cfg.BucketName))
 	}
 
 	// Set bucket policy for better logging
@@ -68,27 +52,27 @@ func createBucketIfNotExists(minioClient *minio.Client) error {
 				"Effect":    "Allow",
 				"Principal": map[string]interface{}{"AWS": []string{"*"}},
 				"Action":    []string{"s3:GetObject"},
-				"Resource":  []string{fmt.Sprintf("arn:aws:s3:::%s/*", bucketName)},
+				"Resource":  []string{fmt.Sprintf("arn:aws:s3:::%s/*", cfg.BucketName)},
 			},
 		},
 	}
 
 	policyJSON, err := json.Marshal(policy)
 	if err != nil {
-		fmt.Printf("⚠️ Could not create policy JSON: %v\n", err)
+		logger.LogIf(ctx, fmt.Errorf("could not create policy JSON: %w", err))
 	} else {
-		err = minioClient.SetBucketPolicy(ctx, bucketName, string(policyJSON))
+		err = minioClient.SetBucketPolicy(ctx, cfg.BucketName, string(policyJSON))
 		if err != nil {
-			fmt.Printf("⚠️ Could not set bucket policy: %v\n", err)
+			logger.LogIf(ctx, fmt.Errorf("could not set bucket policy: %w", err))
 		} else {
-			fmt.Printf("🔒 Set bucket policy for %s\n", bucketName)
+			logger.Info(ctx, fmt.Sprintf("set bucket policy for %s", cfg.BucketName))
 		}
 	}
 
 	return nil
 }
 
-func generateRandomFile() (string, string, int) {
+func generateRandomFile(ctx context.Context, cfg *Config) (string, string, int) {
 	maxSize, _ := rand.Int(rand.Reader, big.NewInt(49000))
 	fileSize := int(maxSize.Int64()) + 1000 // 1KB to 50KB
 	// Generate a realistic filename
@@ -108,24 +92,21 @@ func generateRandomFile() (string, string, int) {
 	}
 	content := contentBuilder.String()
 
-	filePath := filepath.Join(uploadDir, fileName)
+	filePath := filepath.Join(cfg.UploadDir, fileName)
 	err := os.WriteFile(filePath, []byte(content), 0644)
-	if err != nil {
-		log.Fatalf("❌ Error creating file %s: %v", filePath, err)
-	}
+	logger.FatalIf(logger.WithOp(ctx, "write-local-file"), err)
 	return fileName, filePath, len(content)
 }
 
-func uploadFile(minioClient *minio.Client, fileName, filePath string) (int64, error) {
-	ctx := context.Background()
+func uploadFile(minioClient *minio.Client, cfg *Config, fileName, filePath string) (int64, error) {
+	start := time.Now()
+	ctx := logger.WithObject(logger.WithBucket(logger.WithOp(context.Background(), "upload"), cfg.BucketName), fileName)
 
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
-		return 0, fmt.Errorf("❌ Error getting file stats: %v", err)
+		return 0, fmt.Errorf("error getting file stats: %v", err)
 	}
 
-	fmt.Printf("📤 Uploading %s (%d bytes)...\n", fileName, fileInfo.Size())
-
 	// Add metadata to trigger more audit log details
 	metadata := map[string]string{
 		"x-amz-meta-demo-app":    "parseable-minio-demo",
@@ -133,83 +114,39 @@ func uploadFile(minioClient *minio.Client, fileName, filePath string) (int64, er
 		"x-amz-meta-upload-time": time.Now().Format(time.RFC3339),
 	}
 
-	_, err = minioClient.FPutObject(ctx, bucketName, fileName, filePath, minio.PutObjectOptions{
+	_, err = minioClient.FPutObject(ctx, cfg.BucketName, fileName, filePath, minio.PutObjectOptions{
 		ContentType:  "text/plain",
 		UserMetadata: metadata,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("❌ Error uploading file: %v", err)
+		return 0, fmt.Errorf("error uploading file: %v", err)
 	}
 
-	fmt.Printf("✅ Successfully uploaded %s\n", fileName)
+	ctx = logger.WithDuration(logger.WithSize(ctx, fileInfo.Size()), time.Since(start))
+	logger.Info(ctx, "uploaded file")
 
 	// Delete local file after upload
 	err = os.Remove(filePath)
 	if err != nil {
-		fmt.Printf("⚠️ Could not delete local file %s: %v\n", fileName, err)
-	} else {
-		fmt.Printf("🗑️ Deleted local file %s\n", fileName)
-	}
-
-	return fileInfo.Size(), nil
-}
-
-func downloadFile(minioClient *minio.Client, fileName string) (int64, error) {
-	ctx := context.Background()
-	downloadPath := filepath.Join(downloadDir, "downloaded_"+fileName)
-
-	fmt.Printf("📥 Downloading %s...\n", fileName)
-
-	err := minioClient.FGetObject(ctx, bucketName, fileName, downloadPath, minio.GetObjectOptions{})
-	if err != nil {
-		return 0, fmt.Errorf("❌ Error downloading file: %v", err)
-	}
-
-	fmt.Printf("✅ Successfully downloaded %s to %s\n", fileName, downloadPath)
-
-	fileInfo, err := os.Stat(downloadPath)
-	if err != nil {
-		return 0, fmt.Errorf("❌ Error getting downloaded file stats: %v", err)
+		logger.LogIf(ctx, fmt.Errorf("could not delete local file %s: %w", fileName, err))
 	}
 
 	return fileInfo.Size(), nil
 }
 
-func listObjects(minioClient *minio.Client) ([]minio.ObjectInfo, error) {
-	ctx := context.Background()
-	fmt.Printf("📋 Listing objects in bucket %s:\n", bucketName)
-
-	objectsList := []minio.ObjectInfo{}
-	objectsCh := minioClient.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
-		Recursive: true,
-	})
-
-	for object := range objectsCh {
-		if object.Err != nil {
-			return nil, fmt.Errorf("❌ Error listing objects: %v", object.Err)
-		}
-		objectsList = append(objectsList, object)
-		fmt.Printf("   - %s (%d bytes, modified: %s)\n", object.Key, object.Size, object.LastModified)
-	}
-
-	return objectsList, nil
-}
-
-func performAdditionalOperations(minioClient *minio.Client) error {
-	ctx := context.Background()
-	fmt.Println("🔧 Performing additional MinIO operations for audit logs...")
+func performAdditionalOperations(minioClient *minio.Client, cfg *Config) error {
+	ctx := logger.WithBucket(logger.WithOp(context.Background(), "additional-operations"), cfg.BucketName)
+	logger.Info(ctx, "performing additional MinIO operations for audit logs")
 
 	// Get bucket info
-	fmt.Println("📊 Getting bucket information...")
-	policy, err := minioClient.GetBucketPolicy(ctx, bucketName)
+	policy, err := minioClient.GetBucketPolicy(ctx, cfg.BucketName)
 	if err != nil {
-		fmt.Println("⚠️ No bucket policy found (this is normal)")
+		logger.Info(ctx, "no bucket policy found (this is normal)")
 	}
-	fmt.Println("✅ Retrieved bucket policy: ", policy)
+	logger.Info(ctx, fmt.Sprintf("retrieved bucket policy: %s", policy))
 
 	// List objects with prefix
-	fmt.Println("🔍 Listing objects with different parameters...")
-	objectsCh := minioClient.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
+	objectsCh := minioClient.ListObjects(ctx, cfg.BucketName, minio.ListObjectsOptions{
 		Prefix:    "sample",
 		Recursive: false,
 	})
@@ -218,115 +155,103 @@ func performAdditionalOperations(minioClient *minio.Client) error {
 	for range objectsCh {
 		prefixCount++
 	}
-	fmt.Printf("📋 Found %d objects with 'sample' prefix\n", prefixCount)
+	logger.Info(ctx, fmt.Sprintf("found %d objects with 'sample' prefix", prefixCount))
 
 	// Try to access a non-existent object (will generate 404 audit log)
-	_, err = minioClient.GetObject(ctx, bucketName, "non-existent-file.txt", minio.GetObjectOptions{})
+	missingObj, err := minioClient.GetObject(ctx, cfg.BucketName, "non-existent-file.txt", minio.GetObjectOptions{})
+	if err == nil {
+		// GetObject is lazy; Stat sends the request that MinIO answers with 404.
+		_, err = missingObj.Stat()
+		missingObj.Close()
+	}
 	if err != nil {
-		fmt.Println("🔍 Attempted to access non-existent file (generates 404 audit log)")
+		logger.Info(logger.WithObject(ctx, "non-existent-file.txt"), "attempted to access non-existent file (generates 404 audit log)")
 	}
 
 	// Get bucket location
-	location, err := minioClient.GetBucketLocation(ctx, bucketName)
+	bucketLocation, err := minioClient.GetBucketLocation(ctx, cfg.BucketName)
 	if err != nil {
-		fmt.Println("⚠️ Could not get bucket location")
+		logger.LogIf(ctx, fmt.Errorf("could not get bucket location: %w", err))
 	} else {
-		fmt.Printf("🌍 Bucket location: %s\n", location)
+		logger.Info(ctx, fmt.Sprintf("bucket location: %s", bucketLocation))
 	}
 
 	return nil
 }
 
-func runDemo() {
-	fmt.Println("🚀 Starting Parseable + MinIO Demo Application")
-	fmt.Println(strings.Repeat("=", 50))
+func runDemo(cfg *Config) {
+	ctx := logger.WithOp(context.Background(), "demo")
+	logger.Info(ctx, "starting Parseable + MinIO demo application")
 
 	// Ensure directories exist
-	ensureDirExists(uploadDir)
-	ensureDirExists(downloadDir)
+	ensureDirExists(ctx, cfg.UploadDir)
+	ensureDirExists(ctx, cfg.DownloadDir)
 
 	// Initialize MinIO client
-	minioClient, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(accessKeyID, secretAccessKey, ""),
-		Secure: useSSL,
+	minioClient, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  cfg.Credentials(),
+		Secure: cfg.UseSSL,
 	})
-	if err != nil {
-		log.Fatalf("❌ Error initializing MinIO client: %v", err)
-	}
+	logger.FatalIf(ctx, err)
 
 	// Create bucket if it doesn't exist
-	err = createBucketIfNotExists(minioClient)
-	if err != nil {
-		log.Fatalf("❌ Error with bucket operations: %v", err)
-	}
+	err = createBucketIfNotExists(minioClient, cfg)
+	logger.FatalIf(ctx, err)
 
-	// Generate random number of files (1-5)
+	// Seed a handful of files so the workload has existing objects to GET,
+	// STAT and DELETE as soon as it starts.
 	max, _ := rand.Int(rand.Reader, big.NewInt(5))
-	numFiles := int(max.Int64()) + 1
-	fmt.Printf("📁 Generating %d random files...\n", numFiles)
-
-	files := make([]string, numFiles)
-	for i := 0; i < numFiles; i++ {
-		fileName, _, size := generateRandomFile()
-		files[i] = fileName
-		fmt.Printf("   %d. %s (%d bytes)\n", i+1, fileName, size)
-	}
-
-	fmt.Println()
-
-	// Upload all files
-	for _, fileName := range files {
-		filePath := filepath.Join(uploadDir, fileName)
-		_, err := uploadFile(minioClient, fileName, filePath)
-		if err != nil {
-			log.Printf("❌ Error uploading file: %v", err)
+	numSeedFiles := int(max.Int64()) + 1
+	logger.Info(ctx, fmt.Sprintf("seeding %d files before starting the workload", numSeedFiles))
+
+	workload := NewWorkload(minioClient, cfg)
+	for i := 0; i < numSeedFiles; i++ {
+		fileName, filePath, size := generateRandomFile(ctx, cfg)
+		logger.Info(logger.WithObject(ctx, fileName), fmt.Sprintf("seeding file %d/%d (%d bytes)", i+1, numSeedFiles, size))
+		_, err := uploadFile(minioClient, cfg, fileName, filePath)
+		logger.LogIf(ctx, err)
+		if err == nil {
+			workload.addKey(fileName)
 		}
-		time.Sleep(500 * time.Millisecond)
 	}
 
-	fmt.Println()
-
-	// List all objects in bucket
-	_, err = listObjects(minioClient)
-	if err != nil {
-		log.Printf("❌ Error listing objects: %v", err)
-	}
+	// Perform additional operations
+	err = performAdditionalOperations(minioClient, cfg)
+	logger.LogIf(ctx, err)
 
-	fmt.Println()
+	// Exercise object-lock, versioning and retention APIs
+	err = exerciseObjectLockFeatures(minioClient, cfg)
+	logger.LogIf(ctx, err)
 
-	// Download some files back
-	filesToDownload := min(2, len(files))
-	for i := 0; i < filesToDownload; i++ {
-		_, err := downloadFile(minioClient, files[i])
-		if err != nil {
-			log.Printf("❌ Error downloading file: %v", err)
-		}
-		time.Sleep(500 * time.Millisecond)
-	}
+	// Exercise S3 Select and server-side encryption
+	err = exerciseSelectAndEncryption(minioClient, cfg)
+	logger.LogIf(ctx, err)
 
-	fmt.Println()
+	// Bridge bucket create/remove/access events to Parseable as a second,
+	// event-driven ingestion path running alongside MinIO's audit log.
+	exerciseBucketNotifications(ctx, minioClient, cfg)
 
-	// Perform additional operations
-	err = performAdditionalOperations(minioClient)
-	if err != nil {
-		log.Printf("❌ Error in additional operations: %v", err)
-	}
+	logger.Info(ctx, fmt.Sprintf(
+		"check your Parseable dashboard at http://localhost:8000 (streams: minio_audit, minio_log, %s, %s)",
+		cfg.ParseableStream, cfg.ParseableEventsStream,
+	))
+	logger.Info(ctx, "check your MinIO console at http://localhost:9001")
 
-	fmt.Println()
-	fmt.Println("✅ Demo completed successfully!")
-	fmt.Println("🔍 Check your Parseable dashboard at http://localhost:8000")
-	fmt.Println("   - Stream: minio_audit (for MinIO audit logs)")
-	fmt.Println("   - Stream: minio_log (for MinIO server logs)")
-	fmt.Println("🗂️  Check your MinIO console at http://localhost:9001")
-}
+	// Hand off to the continuous workload generator, which replaces the
+	// old one-shot upload/download/list flow with sustained, tunable
+	// traffic for stress-testing Parseable's audit ingestion path.
+	workload.Run(ctx)
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+	logger.Info(ctx, "demo completed successfully")
 }
 
 func main() {
-	runDemo()
+	cfg := LoadConfig()
+	logger.Configure(logger.Target{
+		URL:      cfg.ParseableURL,
+		Username: cfg.ParseableUsername,
+		Password: cfg.ParseablePassword,
+		Stream:   cfg.ParseableStream,
+	})
+	runDemo(cfg)
 }
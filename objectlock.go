@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/RO-29/minio-parseable-audit-logging/logger"
+)
+
+// versionedBucketName derives the name of the object-lock/versioning
+// sandbox bucket from the demo's main bucket.
+func versionedBucketName(cfg *Config) string {
+	return cfg.BucketName + "-versioned"
+}
+
+// randomFutureRetentionDate returns a time between 1 and 30 days from now,
+// used as the "retain until" date for object-lock retention.
+func randomFutureRetentionDate() (time.Time, error) {
+	days, err := rand.Int(rand.Reader, big.NewInt(30))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().AddDate(0, 0, int(days.Int64())+1), nil
+}
+
+// ensureVersionedBucket creates (if needed) a bucket with object locking
+// enabled and switches its versioning on, so retention/legal-hold/version
+// APIs have somewhere to run.
+func ensureVersionedBucket(ctx context.Context, minioClient *minio.Client, cfg *Config) (string, error) {
+	bucket := versionedBucketName(cfg)
+
+	exists, err := minioClient.BucketExists(ctx, bucket)
+	if err != nil {
+		return "", fmt.Errorf("error checking versioned bucket existence: %v", err)
+	}
+
+	if !exists {
+		logger.Info(ctx, fmt.Sprintf("creating object-lock enabled bucket '%s'", bucket))
+		err = minioClient.MakeBucket(ctx, bucket, minio.MakeBucketOptions{
+			Region:        cfg.Location,
+			ObjectLocking: true,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error creating versioned bucket: %v", err)
+		}
+	}
+
+	logger.Info(ctx, fmt.Sprintf("enabling versioning on '%s'", bucket))
+	if err := minioClient.EnableVersioning(ctx, bucket); err != nil {
+		return "", fmt.Errorf("error enabling bucket versioning: %v", err)
+	}
+
+	return bucket, nil
+}
+
+// uploadObjectVersions uploads the same key a handful of times so the
+// bucket accumulates multiple object versions to list and retain.
+func uploadObjectVersions(ctx context.Context, minioClient *minio.Client, bucket, key string, count int) error {
+	for i := 1; i <= count; i++ {
+		content := fmt.Sprintf("version %d of %s generated at %s\n", i, key, time.Now().Format(time.RFC3339))
+		logger.Info(logger.WithObject(ctx, key), fmt.Sprintf("uploading version %d/%d", i, count))
+		_, err := minioClient.PutObject(ctx, bucket, key, strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+			ContentType: "text/plain",
+		})
+		if err != nil {
+			return fmt.Errorf("error uploading version %d of %s: %v", i, key, err)
+		}
+	}
+	return nil
+}
+
+// applyRetentionAndLegalHold applies GOVERNANCE retention with a random
+// future "retain until" date plus a legal hold on key, generating the
+// s3:PutObjectRetention and s3:PutObjectLegalHold audit verbs.
+func applyRetentionAndLegalHold(ctx context.Context, minioClient *minio.Client, bucket, key string) error {
+	retainUntil, err := randomFutureRetentionDate()
+	if err != nil {
+		return fmt.Errorf("error generating retention date: %v", err)
+	}
+
+	mode := minio.Governance
+	logger.Info(logger.WithObject(ctx, key), fmt.Sprintf("applying %s retention until %s", mode, retainUntil.Format(time.RFC3339)))
+	err = minioClient.PutObjectRetention(ctx, bucket, key, minio.PutObjectRetentionOptions{
+		RetainUntilDate: &retainUntil,
+		Mode:            &mode,
+	})
+	if err != nil {
+		return fmt.Errorf("error applying object retention: %v", err)
+	}
+
+	logger.Info(logger.WithObject(ctx, key), "placing legal hold")
+	legalHoldStatus := minio.LegalHoldEnabled
+	err = minioClient.PutObjectLegalHold(ctx, bucket, key, minio.PutObjectLegalHoldOptions{
+		Status: &legalHoldStatus,
+	})
+	if err != nil {
+		return fmt.Errorf("error applying legal hold: %v", err)
+	}
+
+	readMode, readRetainUntil, err := minioClient.GetObjectRetention(ctx, bucket, key, "")
+	if err != nil {
+		logger.LogIf(ctx, fmt.Errorf("could not read back object retention: %w", err))
+	} else {
+		logger.Info(logger.WithObject(ctx, key), fmt.Sprintf("retention: mode=%v until=%v", readMode, readRetainUntil))
+	}
+
+	return nil
+}
+
+// attemptForbiddenDelete tries to delete a retained/legal-held object,
+// which MinIO should reject with a 403 — exactly the kind of denied
+// request that makes for a rich audit-log entry.
+func attemptForbiddenDelete(ctx context.Context, minioClient *minio.Client, bucket, key string) {
+	ctx = logger.WithObject(ctx, key)
+	logger.Info(ctx, "attempting forbidden delete of retained object")
+	// Without a version ID the delete would only add a delete marker, which
+	// retention does not block; target the retained (latest) version itself.
+	info, err := minioClient.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		logger.LogIf(ctx, fmt.Errorf("could not stat retained object: %w", err))
+		return
+	}
+	err = minioClient.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{VersionID: info.VersionID})
+	if err != nil {
+		logger.Info(ctx, fmt.Sprintf("delete correctly denied (generates 403 audit log): %v", err))
+	} else {
+		logger.Info(ctx, "delete unexpectedly succeeded despite retention/legal hold")
+	}
+}
+
+// listObjectVersions lists every version of every object in bucket.
+func listObjectVersions(ctx context.Context, minioClient *minio.Client, bucket string) error {
+	logger.Info(ctx, fmt.Sprintf("listing object versions in '%s'", bucket))
+	objectsCh := minioClient.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Recursive:    true,
+		WithVersions: true,
+	})
+	for object := range objectsCh {
+		if object.Err != nil {
+			return fmt.Errorf("error listing object versions: %v", object.Err)
+		}
+		logger.Info(logger.WithObject(ctx, object.Key), fmt.Sprintf("version %s, latest=%v, size=%d", object.VersionID, object.IsLatest, object.Size))
+	}
+	return nil
+}
+
+// exerciseObjectLockFeatures drives versioning, retention, legal hold, a
+// forbidden delete, and a version listing against a dedicated object-lock
+// enabled bucket, broadening the spectrum of S3 verbs that show up in the
+// minio_audit stream.
+func exerciseObjectLockFeatures(minioClient *minio.Client, cfg *Config) error {
+	ctx := logger.WithOp(context.Background(), "object-lock")
+	logger.Info(ctx, "exercising object-lock, retention and versioning APIs")
+
+	bucket, err := ensureVersionedBucket(ctx, minioClient, cfg)
+	if err != nil {
+		return err
+	}
+
+	const key = "retained-sample.txt"
+	if err := uploadObjectVersions(ctx, minioClient, bucket, key, 3); err != nil {
+		return err
+	}
+
+	if err := applyRetentionAndLegalHold(ctx, minioClient, bucket, key); err != nil {
+		return err
+	}
+
+	attemptForbiddenDelete(ctx, minioClient, bucket, key)
+
+	if err := listObjectVersions(ctx, minioClient, bucket); err != nil {
+		return err
+	}
+
+	return nil
+}
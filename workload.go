@@ -0,0 +1,430 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/minio/minio-go/v7"
+
+	"github.com/RO-29/minio-parseable-audit-logging/logger"
+)
+
+// OpKind identifies one of the S3 verbs the workload generator issues.
+type OpKind string
+
+const (
+	OpPut    OpKind = "PUT"
+	OpGet    OpKind = "GET"
+	OpList   OpKind = "LIST"
+	OpStat   OpKind = "STAT"
+	OpDelete OpKind = "DELETE"
+)
+
+// OpWeight pairs an operation with its share of the traffic mix.
+type OpWeight struct {
+	Op     OpKind
+	Weight float64
+}
+
+// defaultOpMix approximates a realistic read-heavy bucket: mostly PUT and
+// GET, with LIST/STAT/DELETE filling out the rest. It's the fallback used
+// when cfg.WorkloadOpMix is empty or fails to parse.
+var defaultOpMix = []OpWeight{
+	{OpPut, 0.40},
+	{OpGet, 0.30},
+	{OpList, 0.15},
+	{OpStat, 0.10},
+	{OpDelete, 0.05},
+}
+
+// parseOpMix parses a "PUT=40,GET=30,LIST=15,STAT=10,DELETE=5"-style string
+// into an OpWeight slice, so the operation mix can be tuned via
+// WORKLOAD_OP_MIX/-workload-op-mix without recompiling.
+func parseOpMix(s string) ([]OpWeight, error) {
+	var mix []OpWeight
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		opName, weightStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid op-mix entry %q, expected OP=WEIGHT", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight in op-mix entry %q: %v", part, err)
+		}
+		mix = append(mix, OpWeight{Op: OpKind(strings.ToUpper(strings.TrimSpace(opName))), Weight: weight})
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("op-mix %q contains no entries", s)
+	}
+	return mix, nil
+}
+
+// tokenBucket is a simple token-bucket rate limiter shared by every worker,
+// so the aggregate request rate across the whole pool stays near cfg.WorkloadRPS
+// regardless of how many workers are running.
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	ratePerSec float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(ratePerSec float64) *tokenBucket {
+	return &tokenBucket{
+		tokens:     ratePerSec,
+		ratePerSec: ratePerSec,
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.lastRefill).Seconds() * b.ratePerSec
+		// Allow a burst of at least one token so rates below 1 RPS still
+		// accumulate enough to issue a request.
+		burst := b.ratePerSec
+		if burst < 1 {
+			burst = 1
+		}
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.lastRefill = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// opStats accumulates latency and outcome counts for a single operation
+// kind. A handful of fixed buckets stands in for a full latency histogram,
+// which is enough precision for a demo load generator.
+type opStats struct {
+	count      int64
+	errors     int64
+	totalNs    int64
+	under10ms  int64
+	under50ms  int64
+	under250ms int64
+	under1s    int64
+	over1s     int64
+}
+
+func (s *opStats) record(d time.Duration, err error) {
+	atomic.AddInt64(&s.count, 1)
+	atomic.AddInt64(&s.totalNs, d.Nanoseconds())
+	if err != nil {
+		atomic.AddInt64(&s.errors, 1)
+	}
+	switch {
+	case d < 10*time.Millisecond:
+		atomic.AddInt64(&s.under10ms, 1)
+	case d < 50*time.Millisecond:
+		atomic.AddInt64(&s.under50ms, 1)
+	case d < 250*time.Millisecond:
+		atomic.AddInt64(&s.under250ms, 1)
+	case d < time.Second:
+		atomic.AddInt64(&s.under1s, 1)
+	default:
+		atomic.AddInt64(&s.over1s, 1)
+	}
+}
+
+func (s *opStats) summary() string {
+	count := atomic.LoadInt64(&s.count)
+	if count == 0 {
+		return "no requests"
+	}
+	avgMs := float64(atomic.LoadInt64(&s.totalNs)) / float64(count) / float64(time.Millisecond)
+	return fmt.Sprintf("count=%d errors=%d avg=%.1fms buckets[<10ms=%d <50ms=%d <250ms=%d <1s=%d >=1s=%d]",
+		count, atomic.LoadInt64(&s.errors), avgMs,
+		atomic.LoadInt64(&s.under10ms), atomic.LoadInt64(&s.under50ms),
+		atomic.LoadInt64(&s.under250ms), atomic.LoadInt64(&s.under1s), atomic.LoadInt64(&s.over1s))
+}
+
+// Workload runs a continuous, rate-limited mix of S3 operations against a
+// bucket using a pool of workers, standing in for the demo's previous
+// one-shot upload/download/list flow with sustained, tunable traffic.
+type Workload struct {
+	client *minio.Client
+	cfg    *Config
+	mix    []OpWeight
+	limit  *tokenBucket
+
+	keysMu sync.Mutex
+	keys   []string
+
+	stats map[OpKind]*opStats
+
+	completedOps int64
+}
+
+// NewWorkload builds a Workload for cfg's bucket using cfg.WorkloadOpMix, or
+// the default PUT/GET/LIST/STAT/DELETE mix if it's empty or fails to parse.
+func NewWorkload(client *minio.Client, cfg *Config) *Workload {
+	mix := defaultOpMix
+	if cfg.WorkloadOpMix != "" {
+		parsed, err := parseOpMix(cfg.WorkloadOpMix)
+		if err != nil {
+			logger.LogIf(context.Background(), fmt.Errorf("invalid workload op-mix %q, falling back to default: %w", cfg.WorkloadOpMix, err))
+		} else {
+			mix = parsed
+		}
+	}
+
+	stats := make(map[OpKind]*opStats, len(mix))
+	for _, w := range mix {
+		stats[w.Op] = &opStats{}
+	}
+	return &Workload{
+		client: client,
+		cfg:    cfg,
+		mix:    mix,
+		limit:  newTokenBucket(cfg.WorkloadRPS),
+		stats:  stats,
+	}
+}
+
+func (w *Workload) pickOp(rng *rand.Rand) OpKind {
+	total := 0.0
+	for _, ow := range w.mix {
+		total += ow.Weight
+	}
+	r := rng.Float64() * total
+	for _, ow := range w.mix {
+		if r < ow.Weight {
+			return ow.Op
+		}
+		r -= ow.Weight
+	}
+	return w.mix[len(w.mix)-1].Op
+}
+
+func (w *Workload) randomKey(rng *rand.Rand) (string, bool) {
+	w.keysMu.Lock()
+	defer w.keysMu.Unlock()
+	if len(w.keys) == 0 {
+		return "", false
+	}
+	return w.keys[rng.Intn(len(w.keys))], true
+}
+
+func (w *Workload) addKey(key string) {
+	w.keysMu.Lock()
+	defer w.keysMu.Unlock()
+	w.keys = append(w.keys, key)
+}
+
+func (w *Workload) removeKey(key string) {
+	w.keysMu.Lock()
+	defer w.keysMu.Unlock()
+	for i, k := range w.keys {
+		if k == key {
+			w.keys = append(w.keys[:i], w.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+func (w *Workload) doPut(ctx context.Context, rng *rand.Rand) error {
+	key := fmt.Sprintf("workload/%s-%d.txt", gofakeit.Word(), rng.Int63())
+	content := gofakeit.Paragraph(2, 5, 10, " ")
+	_, err := w.client.PutObject(ctx, w.cfg.BucketName, key, strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: "text/plain",
+	})
+	if err == nil {
+		w.addKey(key)
+	}
+	return err
+}
+
+func (w *Workload) doGet(ctx context.Context, rng *rand.Rand) error {
+	key, ok := w.randomKey(rng)
+	if !ok {
+		return w.doPut(ctx, rng)
+	}
+	obj, err := w.client.GetObject(ctx, w.cfg.BucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+	_, err = obj.Stat()
+	return err
+}
+
+func (w *Workload) doStat(ctx context.Context, rng *rand.Rand) error {
+	key, ok := w.randomKey(rng)
+	if !ok {
+		return w.doPut(ctx, rng)
+	}
+	_, err := w.client.StatObject(ctx, w.cfg.BucketName, key, minio.StatObjectOptions{})
+	return err
+}
+
+func (w *Workload) doList(ctx context.Context) error {
+	objectsCh := w.client.ListObjects(ctx, w.cfg.BucketName, minio.ListObjectsOptions{
+		Prefix:    "workload/",
+		Recursive: true,
+		MaxKeys:   50,
+	})
+	for object := range objectsCh {
+		if object.Err != nil {
+			return object.Err
+		}
+	}
+	return nil
+}
+
+func (w *Workload) doDelete(ctx context.Context, rng *rand.Rand) error {
+	key, ok := w.randomKey(rng)
+	if !ok {
+		return w.doPut(ctx, rng)
+	}
+	err := w.client.RemoveObject(ctx, w.cfg.BucketName, key, minio.RemoveObjectOptions{})
+	if err == nil {
+		w.removeKey(key)
+	}
+	return err
+}
+
+// worker pulls tokens off the shared rate limiter and executes a
+// weighted-random operation until ctx is cancelled.
+func (w *Workload) worker(ctx context.Context, id int) {
+	rng := rand.New(rand.NewSource(int64(id) + time.Now().UnixNano()))
+	workerCtx := logger.WithOp(ctx, "workload")
+
+	for {
+		if err := w.limit.Wait(ctx); err != nil {
+			return
+		}
+
+		op := w.pickOp(rng)
+		start := time.Now()
+		var err error
+		switch op {
+		case OpPut:
+			err = w.doPut(ctx, rng)
+		case OpGet:
+			err = w.doGet(ctx, rng)
+		case OpList:
+			err = w.doList(ctx)
+		case OpStat:
+			err = w.doStat(ctx, rng)
+		case OpDelete:
+			err = w.doDelete(ctx, rng)
+		default:
+			err = fmt.Errorf("unknown workload op %q", op)
+		}
+		if stats, ok := w.stats[op]; ok {
+			stats.record(time.Since(start), err)
+		}
+		if err != nil {
+			logger.LogIf(logger.WithOp(workerCtx, string(op)), err)
+		}
+
+		n := atomic.AddInt64(&w.completedOps, 1)
+		if w.cfg.WorkloadMaxOps > 0 && n >= w.cfg.WorkloadMaxOps {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// summarize emits a single INFO record per operation kind with the latency
+// histogram and error count accumulated so far.
+func (w *Workload) summarize(ctx context.Context) {
+	for _, ow := range w.mix {
+		logger.Info(logger.WithOp(ctx, string(ow.Op)), w.stats[ow.Op].summary())
+	}
+}
+
+// Run starts cfg.WorkloadWorkers workers and blocks until the configured
+// duration elapses, the configured number of operations completes, or ctx
+// is cancelled -- whichever happens first.
+func (w *Workload) Run(ctx context.Context) {
+	ctx = logger.WithOp(ctx, "workload")
+	logger.Info(ctx, fmt.Sprintf("starting workload: workers=%d rps=%.1f duration=%s max-ops=%d",
+		w.cfg.WorkloadWorkers, w.cfg.WorkloadRPS, w.cfg.WorkloadDuration, w.cfg.WorkloadMaxOps))
+
+	runCtx := ctx
+	var cancel context.CancelFunc
+	if w.cfg.WorkloadDuration > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, w.cfg.WorkloadDuration)
+		defer cancel()
+	} else {
+		runCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+	}
+
+	summaryTicker := time.NewTicker(w.cfg.WorkloadSummaryInterval)
+	defer summaryTicker.Stop()
+	go func() {
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-summaryTicker.C:
+				w.summarize(ctx)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.WorkloadWorkers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			w.worker(runCtx, id)
+		}(i)
+	}
+
+	// If a max-ops limit is set, stop every worker as soon as it's hit.
+	if w.cfg.WorkloadMaxOps > 0 {
+		go func() {
+			for {
+				select {
+				case <-runCtx.Done():
+					return
+				case <-time.After(50 * time.Millisecond):
+					if atomic.LoadInt64(&w.completedOps) >= w.cfg.WorkloadMaxOps {
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	logger.Info(ctx, fmt.Sprintf("workload finished: %d operations completed", atomic.LoadInt64(&w.completedOps)))
+	w.summarize(ctx)
+}